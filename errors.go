@@ -1,11 +1,21 @@
 package ydb
 
-import "github.com/ydb-platform/ydb-go-sdk/v3/internal/errors"
+import (
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/errors"
+)
 
+// IsTimeoutError reports whether err is (or wraps) a timeout error.
+//
+// Deprecated: use errors.Is(err, ydberr.Timeout) instead.
 func IsTimeoutError(err error) bool {
 	return errors.IsTimeoutError(err)
 }
 
+// IsTransportError reports whether err is (or wraps) a *ydberr.TransportError
+// and, if so, returns its code and name.
+//
+// Deprecated: use errors.As(err, &te) with *ydberr.TransportError instead,
+// which also gives access to the full error value instead of just its code.
 func IsTransportError(err error) (ok bool, code int32, name string) {
 	var t *errors.TransportError
 	if !errors.As(err, &t) {
@@ -14,10 +24,15 @@ func IsTransportError(err error) (ok bool, code int32, name string) {
 	return true, int32(t.Reason), t.Reason.String()
 }
 
+// IsOperationError reports whether err is (or wraps) a *ydberr.OpError and,
+// if so, returns its code and name.
+//
+// Deprecated: use errors.As(err, &oe) with *ydberr.OpError instead, which
+// also gives access to the full error value instead of just its code.
 func IsOperationError(err error) (ok bool, code int32, name string) {
 	var o *errors.OpError
 	if !errors.As(err, &o) {
 		return
 	}
 	return true, int32(o.Reason), o.Reason.String()
-}
\ No newline at end of file
+}
@@ -0,0 +1,205 @@
+// Package backoff implements the retry loop's delay policies: how long to
+// wait before a given attempt, capped so a sleep never outlives the calling
+// context's deadline, plus a Budget that bounds a whole retry loop by
+// attempt count, elapsed time, or total time spent sleeping.
+//
+// This replaces the old BackoffFunc(n int) <-chan time.Time shape, which
+// handed back a fixed channel with no way to account for the context's
+// remaining deadline: a scheduled sleep could (and regularly did, per
+// TestRetryContextDeadline) run well past ctx.Done() before the retry loop
+// ever noticed the cancellation.
+package backoff
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// deadlineSafetyMargin is reserved off the remaining deadline so the caller
+// still has time to observe ctx.Done() and unwind after a capped sleep.
+const deadlineSafetyMargin = 10 * time.Millisecond
+
+// Backoff computes the delay to wait before retry attempt n (0-based). The
+// returned delay is always capped so that it does not extend past ctx's
+// deadline. Implementations must be safe for concurrent use unless stated
+// otherwise.
+//
+// A trace.RetryTrace OnBackoff hook on the caller's retry loop reports the
+// computed delay and the attempt it applies to; it does not report which
+// Backoff implementation or jitter policy produced the delay. Backoff itself
+// only computes the duration.
+type Backoff interface {
+	Delay(attempt int, ctx context.Context) time.Duration
+}
+
+// capToDeadline shrinks delay so it never pushes past ctx's deadline.
+func capToDeadline(ctx context.Context, delay time.Duration) time.Duration {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return delay
+	}
+	remaining := time.Until(deadline) - deadlineSafetyMargin
+	if remaining < 0 {
+		return 0
+	}
+	if delay > remaining {
+		return remaining
+	}
+	return delay
+}
+
+// base2Pow returns base*2^attempt, saturating at the max time.Duration
+// instead of overflowing for large attempt counts.
+func base2Pow(base time.Duration, attempt int) time.Duration {
+	if base <= 0 {
+		return 0
+	}
+	if attempt > 62 {
+		attempt = 62
+	}
+	pow := int64(1) << uint(attempt)
+	const maxDuration = time.Duration(1<<63 - 1)
+	if pow != 0 && base > maxDuration/time.Duration(pow) {
+		return maxDuration
+	}
+	return base * time.Duration(pow)
+}
+
+// NoJitter waits exactly base*2^attempt every time, capped by ctx's
+// deadline. Useful where a deterministic delay sequence is required, e.g.
+// in tests.
+type NoJitter struct {
+	Base time.Duration
+}
+
+func (j NoJitter) Delay(attempt int, ctx context.Context) time.Duration {
+	return capToDeadline(ctx, base2Pow(j.Base, attempt))
+}
+
+// FullJitter waits rand(0, base*2^attempt). See
+// https://aws.amazon.com/blogs/architecture/exponential-backoff-and-jitter/
+type FullJitter struct {
+	Base time.Duration
+}
+
+func (j FullJitter) Delay(attempt int, ctx context.Context) time.Duration {
+	upper := base2Pow(j.Base, attempt)
+	if upper <= 0 {
+		return 0
+	}
+	return capToDeadline(ctx, time.Duration(rand.Int63n(int64(upper))))
+}
+
+// EqualJitter waits base*2^attempt/2 + rand(0, base*2^attempt/2), trading
+// some of FullJitter's spread for a guaranteed minimum delay.
+type EqualJitter struct {
+	Base time.Duration
+}
+
+func (j EqualJitter) Delay(attempt int, ctx context.Context) time.Duration {
+	half := base2Pow(j.Base, attempt) / 2
+	delay := half
+	if half > 0 {
+		delay += time.Duration(rand.Int63n(int64(half)))
+	}
+	return capToDeadline(ctx, delay)
+}
+
+// DecorrelatedJitter waits min(Cap, rand(Base, prev*3)), carrying the
+// previous delay as state across attempts of a single retry loop. A
+// DecorrelatedJitter must not be shared between concurrent retry loops;
+// callers should construct one per loop.
+type DecorrelatedJitter struct {
+	Base time.Duration
+	Cap  time.Duration
+
+	prev time.Duration
+}
+
+func (j *DecorrelatedJitter) Delay(_ int, ctx context.Context) time.Duration {
+	prev := j.prev
+	if prev < j.Base {
+		prev = j.Base
+	}
+	upper := prev * 3
+	delay := j.Base + time.Duration(rand.Int63n(int64(upper-j.Base+1)))
+	if delay > j.Cap {
+		delay = j.Cap
+	}
+	j.prev = delay
+	return capToDeadline(ctx, delay)
+}
+
+// Budget bounds a single retry loop by attempt count, total elapsed time,
+// and total time spent sleeping between attempts. A zero value never
+// short-circuits a loop; it only tracks consumption.
+type Budget struct {
+	MaxAttempts   int
+	MaxElapsed    time.Duration
+	MaxTotalSleep time.Duration
+}
+
+// NewState starts tracking this Budget's consumption for one retry loop,
+// measuring elapsed time from start.
+func (b Budget) NewState(start time.Time) *State {
+	return &State{budget: b, start: start}
+}
+
+// State tracks one retry loop's consumption against its Budget.
+type State struct {
+	budget   Budget
+	start    time.Time
+	attempts int
+	slept    time.Duration
+}
+
+// Attempt records that the loop is about to make another attempt.
+func (s *State) Attempt() {
+	s.attempts++
+}
+
+// Sleep records time spent waiting for the computed backoff delay.
+func (s *State) Sleep(d time.Duration) {
+	s.slept += d
+}
+
+// Exceeded reports whether the Budget has been exhausted by attempt count,
+// elapsed time, or total sleep, and the loop should stop retrying.
+func (s *State) Exceeded() bool {
+	if s.budget.MaxAttempts > 0 && s.attempts >= s.budget.MaxAttempts {
+		return true
+	}
+	if s.budget.MaxElapsed > 0 && time.Since(s.start) >= s.budget.MaxElapsed {
+		return true
+	}
+	if s.budget.MaxTotalSleep > 0 && s.slept >= s.budget.MaxTotalSleep {
+		return true
+	}
+	return false
+}
+
+// Err wraps last (the most recent error the retried operation returned)
+// with the attempt count and elapsed time, for the retry loop to return
+// once Exceeded reports true.
+func (s *State) Err(last error) error {
+	return &ExceededError{Attempts: s.attempts, Elapsed: time.Since(s.start), Err: last}
+}
+
+// ExceededError is returned by a retry loop when its Budget is exhausted
+// before the operation succeeds. It wraps the last error the operation
+// returned, so callers can still errors.As/errors.Is through to it.
+type ExceededError struct {
+	Attempts int
+	Elapsed  time.Duration
+	Err      error
+}
+
+func (e *ExceededError) Error() string {
+	return fmt.Sprintf("retry budget exceeded after %d attempt(s), %s elapsed: %v", e.Attempts, e.Elapsed, e.Err)
+}
+
+func (e *ExceededError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,124 @@
+package backoff
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCapToDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if got := capToDeadline(ctx, time.Second); got > 20*time.Millisecond {
+		t.Errorf("delay %v was not capped to the context deadline", got)
+	}
+
+	ctx2, cancel2 := context.WithTimeout(context.Background(), -time.Millisecond)
+	defer cancel2()
+	if got := capToDeadline(ctx2, time.Second); got != 0 {
+		t.Errorf("delay for an already-expired deadline = %v; want 0", got)
+	}
+
+	if got := capToDeadline(context.Background(), time.Second); got != time.Second {
+		t.Errorf("delay without a deadline should pass through unchanged, got %v", got)
+	}
+}
+
+func TestNoJitterIsDeterministic(t *testing.T) {
+	j := NoJitter{Base: 10 * time.Millisecond}
+	for attempt, want := range map[int]time.Duration{
+		0: 10 * time.Millisecond,
+		1: 20 * time.Millisecond,
+		2: 40 * time.Millisecond,
+	} {
+		if got := j.Delay(attempt, context.Background()); got != want {
+			t.Errorf("attempt %d: Delay() = %v; want %v", attempt, got, want)
+		}
+	}
+}
+
+func TestFullJitterBounds(t *testing.T) {
+	j := FullJitter{Base: 10 * time.Millisecond}
+	max := 10 * time.Millisecond * (1 << 3)
+	for i := 0; i < 100; i++ {
+		got := j.Delay(3, context.Background())
+		if got < 0 || got >= max {
+			t.Fatalf("Delay() = %v; want in [0, %v)", got, max)
+		}
+	}
+}
+
+func TestEqualJitterBounds(t *testing.T) {
+	j := EqualJitter{Base: 10 * time.Millisecond}
+	full := 10 * time.Millisecond * (1 << 3)
+	half := full / 2
+	for i := 0; i < 100; i++ {
+		got := j.Delay(3, context.Background())
+		if got < half || got >= full {
+			t.Fatalf("Delay() = %v; want in [%v, %v)", got, half, full)
+		}
+	}
+}
+
+func TestDecorrelatedJitterBoundsAndState(t *testing.T) {
+	j := &DecorrelatedJitter{Base: 10 * time.Millisecond, Cap: 100 * time.Millisecond}
+	for i := 0; i < 10; i++ {
+		got := j.Delay(i, context.Background())
+		if got < j.Base || got > j.Cap {
+			t.Fatalf("attempt %d: Delay() = %v; want in [%v, %v]", i, got, j.Base, j.Cap)
+		}
+	}
+}
+
+func TestBudgetExceeded(t *testing.T) {
+	for name, tt := range map[string]struct {
+		budget Budget
+		setup  func(s *State)
+	}{
+		"max attempts": {
+			budget: Budget{MaxAttempts: 3},
+			setup: func(s *State) {
+				s.Attempt()
+				s.Attempt()
+				s.Attempt()
+			},
+		},
+		"max total sleep": {
+			budget: Budget{MaxTotalSleep: 50 * time.Millisecond},
+			setup: func(s *State) {
+				s.Sleep(30 * time.Millisecond)
+				s.Sleep(30 * time.Millisecond)
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			s := tt.budget.NewState(time.Now())
+			tt.setup(s)
+			if !s.Exceeded() {
+				t.Fatalf("Exceeded() = false; want true")
+			}
+			err := s.Err(context.DeadlineExceeded)
+			var exceeded *ExceededError
+			if err == nil {
+				t.Fatalf("Err() returned nil")
+			}
+			exceeded, ok := err.(*ExceededError)
+			if !ok {
+				t.Fatalf("Err() returned %T; want *ExceededError", err)
+			}
+			if exceeded.Unwrap() != context.DeadlineExceeded {
+				t.Errorf("Unwrap() = %v; want context.DeadlineExceeded", exceeded.Unwrap())
+			}
+		})
+	}
+}
+
+func TestBudgetZeroValueNeverExceeds(t *testing.T) {
+	s := Budget{}.NewState(time.Now())
+	s.Attempt()
+	s.Sleep(time.Hour)
+	if s.Exceeded() {
+		t.Errorf("Exceeded() = true for a zero Budget; want false")
+	}
+}
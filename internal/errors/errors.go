@@ -0,0 +1,215 @@
+// Package errors is the SDK's internal error vocabulary: the transport and
+// operation error types returned by calls to YDB, and thin aliases over the
+// standard library's errors.New/Is/As so call sites only need one import.
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+)
+
+// New, Is and As are re-exported from the standard library so packages that
+// import internal/errors for TransportError/OpError don't need a second
+// "errors" import.
+var (
+	New = stderrors.New
+	Is  = stderrors.Is
+	As  = stderrors.As
+)
+
+// ReasonMatcher lets an error value outside this package (see the ydberr
+// package's sentinels) participate in errors.Is against a *TransportError
+// or *OpError by reason code, without this package importing back into
+// ydberr. TransportError.Is and OpError.Is call into a ReasonMatcher target
+// instead of comparing reasons directly.
+type ReasonMatcher interface {
+	MatchesTransportError(reason TransportErrorCode) bool
+	MatchesOpError(reason StatusCode) bool
+}
+
+// TransportErrorCode is a gRPC-level status code as reported by a failed
+// call to YDB.
+type TransportErrorCode int
+
+const (
+	TransportErrorUnknownCode TransportErrorCode = iota
+	TransportErrorCanceled
+	TransportErrorUnknown
+	TransportErrorInvalidArgument
+	TransportErrorDeadlineExceeded
+	TransportErrorNotFound
+	TransportErrorAlreadyExists
+	TransportErrorPermissionDenied
+	TransportErrorResourceExhausted
+	TransportErrorFailedPrecondition
+	TransportErrorAborted
+	TransportErrorOutOfRange
+	TransportErrorUnimplemented
+	TransportErrorInternal
+	TransportErrorUnavailable
+	TransportErrorDataLoss
+	TransportErrorUnauthenticated
+)
+
+var transportErrorNames = map[TransportErrorCode]string{
+	TransportErrorUnknownCode:        "unknown code",
+	TransportErrorCanceled:           "canceled",
+	TransportErrorUnknown:            "unknown",
+	TransportErrorInvalidArgument:    "invalid argument",
+	TransportErrorDeadlineExceeded:   "deadline exceeded",
+	TransportErrorNotFound:           "not found",
+	TransportErrorAlreadyExists:      "already exists",
+	TransportErrorPermissionDenied:   "permission denied",
+	TransportErrorResourceExhausted:  "resource exhausted",
+	TransportErrorFailedPrecondition: "failed precondition",
+	TransportErrorAborted:            "aborted",
+	TransportErrorOutOfRange:         "out of range",
+	TransportErrorUnimplemented:      "unimplemented",
+	TransportErrorInternal:           "internal",
+	TransportErrorUnavailable:        "unavailable",
+	TransportErrorDataLoss:           "data loss",
+	TransportErrorUnauthenticated:    "unauthenticated",
+}
+
+func (c TransportErrorCode) String() string {
+	if name, ok := transportErrorNames[c]; ok {
+		return name
+	}
+	return fmt.Sprintf("transport error code %d", int(c))
+}
+
+// TransportError is returned (possibly wrapped) whenever a gRPC call to YDB
+// fails at the transport level.
+type TransportError struct {
+	Reason TransportErrorCode
+}
+
+// NewTransportError builds a *TransportError reporting reason.
+func NewTransportError(reason TransportErrorCode) *TransportError {
+	return &TransportError{Reason: reason}
+}
+
+func (e *TransportError) Error() string {
+	return fmt.Sprintf("transport error: %s", e.Reason)
+}
+
+// Is lets target (typically a ydberr sentinel) match this error by reason
+// code: errors.Is walks err's chain calling Is on each error it finds, so
+// this is the side of the comparison that must do the work - a matching
+// method on target alone is never invoked.
+func (e *TransportError) Is(target error) bool {
+	rm, ok := target.(ReasonMatcher)
+	return ok && rm.MatchesTransportError(e.Reason)
+}
+
+// StatusCode is a YDB operation status code as reported in an operation's
+// result, independent of whether the transport call itself succeeded.
+type StatusCode int
+
+const (
+	StatusUnknownStatus StatusCode = iota
+	StatusBadRequest
+	StatusUnauthorized
+	StatusInternalError
+	StatusAborted
+	StatusUnavailable
+	StatusOverloaded
+	StatusSchemeError
+	StatusGenericError
+	StatusTimeout
+	StatusBadSession
+	StatusPreconditionFailed
+	StatusAlreadyExists
+	StatusNotFound
+	StatusSessionExpired
+	StatusCancelled
+	StatusUndetermined
+	StatusUnsupported
+	StatusSessionBusy
+)
+
+var statusNames = map[StatusCode]string{
+	StatusUnknownStatus:      "unknown status",
+	StatusBadRequest:         "bad request",
+	StatusUnauthorized:       "unauthorized",
+	StatusInternalError:      "internal error",
+	StatusAborted:            "aborted",
+	StatusUnavailable:        "unavailable",
+	StatusOverloaded:         "overloaded",
+	StatusSchemeError:        "scheme error",
+	StatusGenericError:       "generic error",
+	StatusTimeout:            "timeout",
+	StatusBadSession:         "bad session",
+	StatusPreconditionFailed: "precondition failed",
+	StatusAlreadyExists:      "already exists",
+	StatusNotFound:           "not found",
+	StatusSessionExpired:     "session expired",
+	StatusCancelled:          "cancelled",
+	StatusUndetermined:       "undetermined",
+	StatusUnsupported:        "unsupported",
+	StatusSessionBusy:        "session busy",
+}
+
+func (c StatusCode) String() string {
+	if name, ok := statusNames[c]; ok {
+		return name
+	}
+	return fmt.Sprintf("status code %d", int(c))
+}
+
+// OpError is returned (possibly wrapped) whenever a YDB operation completes
+// with a non-success status.
+type OpError struct {
+	Reason StatusCode
+}
+
+// OpErrorOption configures an *OpError built by NewOpError.
+type OpErrorOption func(*OpError)
+
+// WithOEReason sets the OpError's status code.
+func WithOEReason(reason StatusCode) OpErrorOption {
+	return func(e *OpError) {
+		e.Reason = reason
+	}
+}
+
+// NewOpError builds an *OpError, applying every opt in order.
+func NewOpError(opts ...OpErrorOption) *OpError {
+	e := &OpError{}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+func (e *OpError) Error() string {
+	return fmt.Sprintf("operation error: %s", e.Reason)
+}
+
+// Is lets target (typically a ydberr sentinel) match this error by reason
+// code; see TransportError.Is for why the logic lives here rather than on
+// target.
+func (e *OpError) Is(target error) bool {
+	rm, ok := target.(ReasonMatcher)
+	return ok && rm.MatchesOpError(e.Reason)
+}
+
+// IsOpError reports whether err is (or wraps) an *OpError reporting reason.
+func IsOpError(err error, reason StatusCode) bool {
+	var o *OpError
+	return As(err, &o) && o.Reason == reason
+}
+
+// IsTransportError reports whether err is (or wraps) a *TransportError
+// reporting reason.
+func IsTransportError(err error, reason TransportErrorCode) bool {
+	var t *TransportError
+	return As(err, &t) && t.Reason == reason
+}
+
+// IsTimeoutError reports whether err is (or wraps) an error representing a
+// timeout: a *TransportError with reason DeadlineExceeded, or an *OpError
+// with reason StatusTimeout.
+func IsTimeoutError(err error) bool {
+	return IsTransportError(err, TransportErrorDeadlineExceeded) || IsOpError(err, StatusTimeout)
+}
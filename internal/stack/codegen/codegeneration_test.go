@@ -0,0 +1,94 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+)
+
+func TestCollectFunctionIDArgsCoversFullAST(t *testing.T) {
+	for name, src := range map[string]string{
+		"send statement": `package p
+func f(ch chan string) {
+	ch <- stack.FunctionID("")
+}`,
+		"go statement": `package p
+func f() {
+	go stack.FunctionID("")
+}`,
+		"defer statement": `package p
+func f() {
+	defer stack.FunctionID("")
+}`,
+		"labeled statement": `package p
+func f() {
+loop:
+	stack.FunctionID("")
+	goto loop
+}`,
+		"inc dec statement": `package p
+func f() {
+	arr := []int{0}
+	arr[stack.FunctionID("")]++
+}`,
+		"switch statement tag": `package p
+func f() {
+	switch stack.FunctionID("") {
+	default:
+	}
+}`,
+		"type assert expr": `package p
+func f() {
+	_ = interface{}(stack.FunctionID("")).(string)
+}`,
+		"slice expr bounds": `package p
+func f(s []byte) {
+	_ = s[idx(stack.FunctionID("")):]
+}`,
+		"key value expr in composite lit": `package p
+func f() {
+	_ = map[string]string{"k": val(stack.FunctionID(""))}
+}`,
+		"array type length": `package p
+func f() {
+	var arr [idx(stack.FunctionID(""))]byte
+	_ = arr
+}`,
+		"func decl param type": `package p
+func f(x [idx(stack.FunctionID(""))]byte) {
+	_ = x
+}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			fset := token.NewFileSet()
+			file, err := parser.ParseFile(fset, "src.go", src, 0)
+			if err != nil {
+				t.Fatalf("ParseFile: %v", err)
+			}
+			args := collectFunctionIDArgs(fset, file)
+			if len(args) != 1 {
+				t.Fatalf("collectFunctionIDArgs() = %d args; want 1", len(args))
+			}
+			if args[0].ArgPos >= args[0].ArgEnd {
+				t.Errorf("invalid arg span: [%d, %d)", args[0].ArgPos, args[0].ArgEnd)
+			}
+		})
+	}
+}
+
+func TestCollectFunctionIDArgsIgnoresUnrelatedCalls(t *testing.T) {
+	src := `package p
+func f() {
+	other.FunctionID("a")
+	stack.OtherFunc("b")
+	stack.FunctionID("a", "b")
+}`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "src.go", src, 0)
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+	if args := collectFunctionIDArgs(fset, file); len(args) != 0 {
+		t.Fatalf("collectFunctionIDArgs() = %d args; want 0", len(args))
+	}
+}
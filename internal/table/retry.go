@@ -0,0 +1,275 @@
+package table
+
+import (
+	"context"
+	"time"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/backoff"
+	ierrors "github.com/ydb-platform/ydb-go-sdk/v3/internal/errors"
+	"github.com/ydb-platform/ydb-go-sdk/v3/trace"
+)
+
+// RetryOperation is called by SessionProvider.Retry (and RetryTx) with a
+// Session obtained from the provider; returning a non-nil error may cause
+// the provider to retry with a fresh call to op.
+type RetryOperation func(ctx context.Context, s *Session) error
+
+// SessionProvider gets and puts back Sessions, and knows how to retry an
+// operation across them.
+type SessionProvider interface {
+	Get(ctx context.Context) (*Session, error)
+	Put(ctx context.Context, s *Session) error
+	Retry(ctx context.Context, isOperationIdempotent bool, op RetryOperation) error
+}
+
+// SessionProviderFunc implements SessionProvider from plain funcs, so tests
+// can inject fake Get/Put/Retry behavior the same way a real pool wires up
+// the production ones. If OnRetry is nil, Retry falls back to retryBackoff
+// using FastBackoff/SlowBackoff/Budget (each defaulted if unset).
+type SessionProviderFunc struct {
+	OnGet   func(ctx context.Context) (*Session, error)
+	OnPut   func(ctx context.Context, s *Session) error
+	OnRetry func(ctx context.Context, op RetryOperation) error
+
+	// FastBackoff paces the immediate-retry classes of error (e.g. a busy
+	// session): no wait is expected beyond whatever FastBackoff itself
+	// computes. Defaults to a low-base FullJitter.
+	FastBackoff backoff.Backoff
+	// SlowBackoff paces the wait-retry classes of error (overloaded,
+	// unavailable, aborted). Defaults to a high-base FullJitter.
+	SlowBackoff backoff.Backoff
+	// Budget bounds the whole retry loop. nil (the default) applies
+	// defaultRetryBudget when ctx has no deadline (e.g. context.Background()),
+	// so a persistently failing retryable error still gives up eventually
+	// instead of retrying forever; when ctx has a deadline, nil applies no
+	// budget and the deadline alone bounds the loop. Pass a non-nil *backoff.Budget
+	// - including &backoff.Budget{} for "never give up" - to override it.
+	Budget *backoff.Budget
+}
+
+func (p SessionProviderFunc) Get(ctx context.Context) (*Session, error) {
+	return p.OnGet(ctx)
+}
+
+func (p SessionProviderFunc) Put(ctx context.Context, s *Session) error {
+	if p.OnPut == nil {
+		return nil
+	}
+	return p.OnPut(ctx, s)
+}
+
+func (p SessionProviderFunc) Retry(ctx context.Context, isOperationIdempotent bool, op RetryOperation) error {
+	if p.OnRetry != nil {
+		return p.OnRetry(ctx, op)
+	}
+	return retryBackoff(ctx, p, p.fastBackoff(), p.slowBackoff(), isOperationIdempotent, op)
+}
+
+func (p SessionProviderFunc) fastBackoff() backoff.Backoff {
+	if p.FastBackoff != nil {
+		return p.FastBackoff
+	}
+	return backoff.FullJitter{Base: 5 * time.Millisecond}
+}
+
+func (p SessionProviderFunc) slowBackoff() backoff.Backoff {
+	if p.SlowBackoff != nil {
+		return p.SlowBackoff
+	}
+	return backoff.FullJitter{Base: 500 * time.Millisecond}
+}
+
+// defaultRetryBudget caps an otherwise-unbounded retry loop: without it, a
+// context with no deadline (context.Background()) plus a persistently
+// failing retryable error retries forever instead of ever returning.
+var defaultRetryBudget = backoff.Budget{MaxAttempts: 10, MaxElapsed: 30 * time.Second}
+
+func (p SessionProviderFunc) retryBudget(ctx context.Context) backoff.Budget {
+	if p.Budget != nil {
+		return *p.Budget
+	}
+	if _, hasDeadline := ctx.Deadline(); hasDeadline {
+		// The caller already bounds this loop with ctx; don't also clamp it
+		// to the shorter defaultRetryBudget underneath an explicit,
+		// possibly much more generous, deadline.
+		return backoff.Budget{}
+	}
+	return defaultRetryBudget
+}
+
+// budgetProvider is implemented by SessionProviderFunc so retryBackoff can
+// read a caller-configured Budget off whatever SessionProvider it was
+// given, without widening the SessionProvider interface itself.
+type budgetProvider interface {
+	retryBudget(ctx context.Context) backoff.Budget
+}
+
+func budgetFor(ctx context.Context, p SessionProvider) backoff.Budget {
+	if bp, ok := p.(budgetProvider); ok {
+		return bp.retryBudget(ctx)
+	}
+	return backoff.Budget{}
+}
+
+// SingleSession returns a SessionProvider that always hands back the same
+// Session and paces wait-retries with slowBackoff.
+func SingleSession(s *Session, slowBackoff backoff.Backoff) SessionProvider {
+	return SessionProviderFunc{
+		OnGet: func(context.Context) (*Session, error) {
+			return s, nil
+		},
+		SlowBackoff: slowBackoff,
+	}
+}
+
+type retryMode int
+
+const (
+	retryNone retryMode = iota
+	retryFast
+	retryWait
+	retryDeleteSession
+)
+
+// classify decides how retryBackoff should react to err. A session-level
+// abort (bad/expired session) forces a fresh session; a capacity signal
+// (overloaded/unavailable/aborted) waits out a backoff delay first; a
+// session merely being busy retries immediately without discarding it; an
+// undetermined outcome is only retried for operations the caller has
+// declared idempotent, since retrying otherwise could double-apply it.
+// Anything else is terminal.
+func classify(err error, isOperationIdempotent bool) retryMode {
+	switch {
+	case ierrors.IsOpError(err, ierrors.StatusBadSession),
+		ierrors.IsOpError(err, ierrors.StatusSessionExpired):
+		return retryDeleteSession
+	case ierrors.IsOpError(err, ierrors.StatusSessionBusy):
+		return retryFast
+	case ierrors.IsOpError(err, ierrors.StatusUndetermined):
+		if isOperationIdempotent {
+			return retryWait
+		}
+		return retryNone
+	case ierrors.IsOpError(err, ierrors.StatusOverloaded),
+		ierrors.IsOpError(err, ierrors.StatusUnavailable),
+		ierrors.IsOpError(err, ierrors.StatusAborted),
+		ierrors.IsTransportError(err, ierrors.TransportErrorResourceExhausted),
+		ierrors.IsTransportError(err, ierrors.TransportErrorUnavailable),
+		ierrors.IsTransportError(err, ierrors.TransportErrorAborted):
+		return retryWait
+	default:
+		return retryNone
+	}
+}
+
+// retryBackoff drives one retry loop: get a session from p, call op,
+// classify any error it returns, and either stop, retry immediately with a
+// fresh session (bad or expired session), or wait out a delay from
+// slowBackoff/fastBackoff before retrying - that
+// delay is always capped to ctx's remaining deadline (see
+// internal/backoff), so a retry loop never oversleeps past a caller's
+// timeout the way a bare BackoffFunc(n int) <-chan time.Time channel did.
+// budgetFor(p) additionally bounds the whole loop by attempt count, elapsed
+// time, or total sleep.
+func retryBackoff(
+	ctx context.Context,
+	p SessionProvider,
+	fastBackoff, slowBackoff backoff.Backoff,
+	isOperationIdempotent bool,
+	op RetryOperation,
+) (err error) {
+	var (
+		t        = trace.ContextRetryTrace(ctx)
+		start    = time.Now()
+		attempts int
+		state    = budgetFor(ctx, p).NewState(start)
+	)
+	if t.OnRetry != nil {
+		if onDone := t.OnRetry(trace.RetryLoopStartInfo{Context: ctx}); onDone != nil {
+			defer func() {
+				onDone(trace.RetryLoopDoneInfo{
+					Context:  ctx,
+					Latency:  time.Since(start),
+					Attempts: attempts,
+					Error:    err,
+				})
+			}()
+		}
+	}
+
+	for {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			if err != nil {
+				// ctx ended while we were between attempts (e.g. during the
+				// Put/close after a retryable error); report that error,
+				// consistent with the mid-wait cancellation path below,
+				// rather than ctx.Err() from a prior attempt that already
+				// had a more actionable error of its own.
+				return err
+			}
+			return ctxErr
+		}
+
+		attempts++
+		state.Attempt()
+
+		s, getErr := p.Get(ctx)
+		if getErr != nil {
+			return getErr
+		}
+
+		err = op(ctx, s)
+		if err == nil {
+			_ = p.Put(ctx, s)
+			return nil
+		}
+
+		mode := classify(err, isOperationIdempotent)
+		if mode == retryNone {
+			_ = p.Put(ctx, s)
+			return err
+		}
+
+		if mode == retryDeleteSession {
+			s.close()
+		} else {
+			_ = p.Put(ctx, s)
+		}
+
+		if state.Exceeded() {
+			return state.Err(err)
+		}
+
+		if mode == retryDeleteSession {
+			// The bad session is already closed and the next Get hands back
+			// another one, so there is no delay to wait out.
+			continue
+		}
+
+		delayBackoff := fastBackoff
+		if mode == retryWait {
+			delayBackoff = slowBackoff
+		}
+		delay := delayBackoff.Delay(attempts, ctx)
+		if t.OnBackoff != nil {
+			t.OnBackoff(trace.RetryBackoffInfo{Context: ctx, Attempt: attempts, Delay: delay})
+		}
+		state.Sleep(delay)
+		if delay <= 0 {
+			if ctx.Err() != nil {
+				// delayBackoff (e.g. a channel-based adapter) observed ctx
+				// end while computing the delay; report the operation's
+				// own error rather than ctx.Err(), since it's the more
+				// actionable one and the one callers already check for
+				// with errors.Is against a specific reason.
+				return err
+			}
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+	}
+}
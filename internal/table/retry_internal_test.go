@@ -0,0 +1,235 @@
+package table
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/backoff"
+	ierrors "github.com/ydb-platform/ydb-go-sdk/v3/internal/errors"
+	"github.com/ydb-platform/ydb-go-sdk/v3/testutil"
+)
+
+func TestRetryBackoffImmediateSuccess(t *testing.T) {
+	p := SingleSession(simpleSession(), backoff.NoJitter{})
+	attempts := 0
+	err := p.Retry(context.Background(), false, func(context.Context, *Session) error {
+		attempts++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Retry() = %v; want nil", err)
+	}
+	if attempts != 1 {
+		t.Errorf("attempts = %d; want 1", attempts)
+	}
+}
+
+func TestRetryBackoffRetriesWaitClassUntilSuccess(t *testing.T) {
+	p := SingleSession(simpleSession(), backoff.NoJitter{Base: time.Millisecond})
+	attempts := 0
+	err := p.Retry(context.Background(), false, func(context.Context, *Session) error {
+		attempts++
+		if attempts < 3 {
+			return ierrors.NewOpError(ierrors.WithOEReason(ierrors.StatusOverloaded))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Retry() = %v; want nil", err)
+	}
+	if attempts != 3 {
+		t.Errorf("attempts = %d; want 3", attempts)
+	}
+}
+
+func TestRetryBackoffTerminalErrorReturnsImmediately(t *testing.T) {
+	p := SingleSession(simpleSession(), backoff.NoJitter{})
+	wantErr := ierrors.New("not retryable")
+	attempts := 0
+	err := p.Retry(context.Background(), false, func(context.Context, *Session) error {
+		attempts++
+		return wantErr
+	})
+	if !ierrors.Is(err, wantErr) {
+		t.Fatalf("Retry() = %v; want %v", err, wantErr)
+	}
+	if attempts != 1 {
+		t.Errorf("attempts = %d; want 1", attempts)
+	}
+}
+
+func TestRetryBackoffDeletesBadSession(t *testing.T) {
+	sessions := []*Session{newSession(nil, "1"), newSession(nil, "2")}
+	next := 0
+	var puts []*Session
+	p := SessionProviderFunc{
+		OnGet: func(context.Context) (*Session, error) {
+			s := sessions[next]
+			next++
+			return s, nil
+		},
+		OnPut: func(_ context.Context, s *Session) error {
+			puts = append(puts, s)
+			return nil
+		},
+		FastBackoff: backoff.NoJitter{},
+	}
+	err := p.Retry(context.Background(), false, func(_ context.Context, s *Session) error {
+		if s == sessions[0] {
+			return ierrors.NewOpError(ierrors.WithOEReason(ierrors.StatusBadSession))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Retry() = %v; want nil", err)
+	}
+	if !sessions[0].closed {
+		t.Errorf("bad session was not closed")
+	}
+	if sessions[1].closed {
+		t.Errorf("good session was closed")
+	}
+	for _, s := range puts {
+		if s == sessions[0] {
+			t.Errorf("Put() was called with the closed bad session; it must never be reused")
+		}
+	}
+}
+
+func TestRetryBackoffCapsDelayToDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	p := SingleSession(simpleSession(), backoff.NoJitter{Base: time.Second})
+	start := time.Now()
+	_ = p.Retry(ctx, false, func(context.Context, *Session) error {
+		return ierrors.NewOpError(ierrors.WithOEReason(ierrors.StatusOverloaded))
+	})
+	if latency := time.Since(start); latency > 200*time.Millisecond {
+		t.Errorf("Retry() took %v; delays should have been capped to the 50ms deadline", latency)
+	}
+}
+
+func TestRetryBackoffBudgetExceeded(t *testing.T) {
+	p := SessionProviderFunc{
+		OnGet: func(context.Context) (*Session, error) {
+			return simpleSession(), nil
+		},
+		FastBackoff: backoff.NoJitter{},
+		SlowBackoff: backoff.NoJitter{},
+		Budget:      &backoff.Budget{MaxAttempts: 2},
+	}
+	err := p.Retry(context.Background(), false, func(context.Context, *Session) error {
+		return ierrors.NewOpError(ierrors.WithOEReason(ierrors.StatusOverloaded))
+	})
+	var exceeded *backoff.ExceededError
+	if !ierrors.As(err, &exceeded) {
+		t.Fatalf("Retry() = %v; want a *backoff.ExceededError", err)
+	}
+	if exceeded.Attempts != 2 {
+		t.Errorf("Attempts = %d; want 2", exceeded.Attempts)
+	}
+}
+
+// TestRetryBackoffDefaultBudgetBoundsUnboundedContext proves that, absent an
+// explicit Budget, a persistently failing wait-class error under a
+// context.Background() (no deadline) still gives up eventually instead of
+// retrying forever - the scenario a zero-value Budget used to let through.
+func TestRetryBackoffDefaultBudgetBoundsUnboundedContext(t *testing.T) {
+	p := SessionProviderFunc{
+		OnGet: func(context.Context) (*Session, error) {
+			return simpleSession(), nil
+		},
+		FastBackoff: backoff.NoJitter{},
+		SlowBackoff: backoff.NoJitter{},
+	}
+	attempts := 0
+	err := p.Retry(context.Background(), false, func(context.Context, *Session) error {
+		attempts++
+		return ierrors.NewOpError(ierrors.WithOEReason(ierrors.StatusOverloaded))
+	})
+	var exceeded *backoff.ExceededError
+	if !ierrors.As(err, &exceeded) {
+		t.Fatalf("Retry() = %v; want a *backoff.ExceededError", err)
+	}
+	if attempts != exceeded.Attempts {
+		t.Errorf("attempts = %d; want %d (exceeded.Attempts)", attempts, exceeded.Attempts)
+	}
+}
+
+// TestRetryBackoffDefaultBudgetDefersToCtxDeadline proves the default budget
+// doesn't also clamp a caller-supplied ctx deadline down to its own, shorter
+// MaxElapsed: with an explicit deadline in play, retryBackoff should keep
+// retrying until that deadline, not bail out after defaultRetryBudget's
+// MaxElapsed.
+func TestRetryBackoffDefaultBudgetDefersToCtxDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 80*time.Millisecond)
+	defer cancel()
+
+	p := SessionProviderFunc{
+		OnGet: func(context.Context) (*Session, error) {
+			return simpleSession(), nil
+		},
+		FastBackoff: backoff.NoJitter{},
+		SlowBackoff: backoff.NoJitter{},
+	}
+	attempts := 0
+	err := p.Retry(ctx, false, func(context.Context, *Session) error {
+		attempts++
+		return ierrors.NewOpError(ierrors.WithOEReason(ierrors.StatusOverloaded))
+	})
+	var exceeded *backoff.ExceededError
+	if ierrors.As(err, &exceeded) {
+		t.Fatalf("Retry() = %v; want the operation's own error, not a budget ExceededError - ctx's deadline should govern, not defaultRetryBudget", err)
+	}
+	if !ierrors.IsOpError(err, ierrors.StatusOverloaded) {
+		t.Errorf("Retry() = %v; want the classified StatusOverloaded OpError", err)
+	}
+	if attempts <= defaultRetryBudget.MaxAttempts {
+		t.Errorf("attempts = %d; want more than defaultRetryBudget.MaxAttempts (%d) since ctx's own deadline should govern", attempts, defaultRetryBudget.MaxAttempts)
+	}
+}
+
+// TestRetryBackoffFuncAdapterObservesWaitThenCancel mirrors the handshake the
+// pre-existing channel-based TestRetryerBackoffRetryCancelation relies on: a
+// testutil.BackoffFunc signals that a wait is in flight by blocking the test
+// goroutine on a channel it controls, which lets the test cancel ctx only
+// once it knows retryBackoff is actually inside the wait. It proves
+// testutil.BackoffFunc satisfies backoff.Backoff and that cancellation during
+// the wait surfaces the operation's own error rather than ctx.Err().
+func TestRetryBackoffFuncAdapterObservesWaitThenCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	waiting := make(chan struct{})
+	release := make(chan time.Time)
+	slowBackoff := testutil.BackoffFunc(func(int) <-chan time.Time {
+		close(waiting)
+		return release
+	})
+
+	wantErr := ierrors.NewOpError(ierrors.WithOEReason(ierrors.StatusOverloaded))
+	p := SessionProviderFunc{
+		OnGet: func(context.Context) (*Session, error) {
+			return simpleSession(), nil
+		},
+		FastBackoff: backoff.NoJitter{},
+		SlowBackoff: slowBackoff,
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- p.Retry(ctx, false, func(context.Context, *Session) error {
+			return wantErr
+		})
+	}()
+
+	<-waiting
+	cancel()
+
+	err := <-done
+	if !ierrors.Is(err, wantErr) {
+		t.Fatalf("Retry() = %v; want %v", err, wantErr)
+	}
+}
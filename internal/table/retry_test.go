@@ -10,6 +10,7 @@ import (
 
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/errors"
 	"github.com/ydb-platform/ydb-go-sdk/v3/trace"
+	"github.com/ydb-platform/ydb-go-sdk/v3/ydberr"
 
 	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb_Table"
 	"google.golang.org/protobuf/proto"
@@ -126,7 +127,7 @@ func TestRetryerBadSession(t *testing.T) {
 			return errors.NewOpError(errors.WithOEReason(errors.StatusBadSession))
 		},
 	)
-	if !errors.IsOpError(err, errors.StatusBadSession) {
+	if !errors.Is(err, ydberr.BadSession) {
 		t.Errorf("unexpected error: %v", err)
 	}
 	seen := make(map[*Session]bool, len(sessions))
@@ -384,4 +385,4 @@ func TestRetryContextDeadline(t *testing.T) {
 			})
 		}
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,89 @@
+package table
+
+import (
+	"context"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/trace"
+)
+
+// TxOperation is the user callback passed to RetryTx: it receives a
+// Transaction already opened against a fresh Session and returns an error
+// to trigger a rollback (and, for the abort classes classify already treats
+// as retryable, a full transaction restart on the next attempt) or nil to
+// commit.
+type TxOperation func(ctx context.Context, tx *Transaction) error
+
+// RetryTx is Retry's sibling for transactional work: it opens a transaction
+// before calling op, commits on success, and rolls back on any error op
+// returns. Because Retry re-enters its callback from scratch on every
+// attempt, a retried RetryTx call restarts the whole transaction rather
+// than replaying a single statement - the only correct choice once a
+// session reports StatusAborted, StatusBadSession, or a transport-level
+// abort, since a partially-applied transaction cannot be resumed mid-flight.
+//
+// When txSettings requests a read-only snapshot (ReadOnly && Snapshot), a
+// successful op skips the commit round-trip entirely: a read-only snapshot
+// has nothing to persist, so ending the transaction server-side would be a
+// pure no-op round-trip.
+func RetryTx(
+	ctx context.Context,
+	p SessionProvider,
+	txSettings TxSettings,
+	isOperationIdempotent bool,
+	op TxOperation,
+) error {
+	var (
+		attempt int
+		lastErr error
+	)
+	return p.Retry(ctx, isOperationIdempotent, func(ctx context.Context, s *Session) (err error) {
+		attempt++
+		if attempt > 1 {
+			if t := trace.ContextRetryTrace(ctx); t.OnTxRestart != nil {
+				t.OnTxRestart(trace.RetryTxRestartInfo{Context: ctx, Attempt: attempt, Error: lastErr})
+			}
+		}
+		defer func() { lastErr = err }()
+
+		tx, err := s.BeginTransaction(ctx, txSettings)
+		if err != nil {
+			return err
+		}
+
+		succeeded := false
+		defer EndTransaction(ctx, tx, &succeeded)
+
+		if err := op(ctx, tx); err != nil {
+			return err
+		}
+
+		if txSettings.ReadOnly && txSettings.Snapshot {
+			succeeded = true
+			return nil
+		}
+
+		if _, err := tx.CommitTx(ctx); err != nil {
+			return err
+		}
+		succeeded = true
+
+		return nil
+	})
+}
+
+// EndTransaction rolls tx back unless succeeded reports true, in which case
+// it does nothing (the caller is expected to have already committed, or to
+// have taken the read-only snapshot fast path). Run it under defer, set
+// succeeded only once nothing can fail anymore, so a rollback still happens
+// if op - or the commit itself - panics or errors:
+//
+//	succeeded := false
+//	defer EndTransaction(ctx, tx, &succeeded)
+//	...
+//	succeeded = true
+func EndTransaction(ctx context.Context, tx *Transaction, succeeded *bool) {
+	if succeeded != nil && *succeeded {
+		return
+	}
+	_ = tx.Rollback(ctx)
+}
@@ -0,0 +1,141 @@
+package table
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/backoff"
+	ierrors "github.com/ydb-platform/ydb-go-sdk/v3/internal/errors"
+)
+
+func TestRetryTxCommitsOnSuccess(t *testing.T) {
+	var committed, rolledBack bool
+	s := simpleSession()
+	s.onCommitTx = func(context.Context, *Transaction) error {
+		committed = true
+		return nil
+	}
+	s.onRollback = func(context.Context, *Transaction) error {
+		rolledBack = true
+		return nil
+	}
+
+	p := SingleSession(s, backoff.NoJitter{})
+	err := RetryTx(context.Background(), p, TxSettings{}, false, func(context.Context, *Transaction) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("RetryTx() = %v; want nil", err)
+	}
+	if !committed {
+		t.Errorf("transaction was not committed")
+	}
+	if rolledBack {
+		t.Errorf("committed transaction was also rolled back")
+	}
+}
+
+func TestRetryTxRollsBackOnError(t *testing.T) {
+	var committed, rolledBack bool
+	s := simpleSession()
+	s.onCommitTx = func(context.Context, *Transaction) error {
+		committed = true
+		return nil
+	}
+	s.onRollback = func(context.Context, *Transaction) error {
+		rolledBack = true
+		return nil
+	}
+
+	p := SingleSession(s, backoff.NoJitter{})
+	wantErr := ierrors.New("op failed")
+	err := RetryTx(context.Background(), p, TxSettings{}, false, func(context.Context, *Transaction) error {
+		return wantErr
+	})
+	if !ierrors.Is(err, wantErr) {
+		t.Fatalf("RetryTx() = %v; want %v", err, wantErr)
+	}
+	if committed {
+		t.Errorf("failed transaction was committed")
+	}
+	if !rolledBack {
+		t.Errorf("failed transaction was not rolled back")
+	}
+}
+
+func TestRetryTxRollsBackOnCommitFailure(t *testing.T) {
+	var rolledBack bool
+	s := simpleSession()
+	s.onCommitTx = func(context.Context, *Transaction) error {
+		return ierrors.New("commit failed")
+	}
+	s.onRollback = func(context.Context, *Transaction) error {
+		rolledBack = true
+		return nil
+	}
+
+	p := SingleSession(s, backoff.NoJitter{})
+	err := RetryTx(context.Background(), p, TxSettings{}, false, func(context.Context, *Transaction) error {
+		return nil
+	})
+	if err == nil {
+		t.Fatal("RetryTx() = nil; want the commit error")
+	}
+	if !rolledBack {
+		t.Errorf("transaction whose commit failed was not rolled back")
+	}
+}
+
+func TestRetryTxRestartsWholeTransactionOnAborted(t *testing.T) {
+	begins := 0
+	s := simpleSession()
+	s.onBeginTransaction = func(_ context.Context, settings TxSettings) (*Transaction, error) {
+		begins++
+		return &Transaction{session: s, Settings: settings}, nil
+	}
+
+	p := SingleSession(s, backoff.NoJitter{})
+	attempt := 0
+	err := RetryTx(context.Background(), p, TxSettings{}, false, func(context.Context, *Transaction) error {
+		attempt++
+		if attempt == 1 {
+			return ierrors.NewOpError(ierrors.WithOEReason(ierrors.StatusAborted))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("RetryTx() = %v; want nil", err)
+	}
+	if begins != 2 {
+		t.Errorf("BeginTransaction was called %d times; want 2 (one per attempt)", begins)
+	}
+}
+
+func TestRetryTxReadOnlySnapshotSkipsCommit(t *testing.T) {
+	var committed, rolledBack bool
+	s := simpleSession()
+	s.onCommitTx = func(context.Context, *Transaction) error {
+		committed = true
+		return nil
+	}
+	s.onRollback = func(context.Context, *Transaction) error {
+		rolledBack = true
+		return nil
+	}
+
+	p := SingleSession(s, backoff.NoJitter{})
+	err := RetryTx(context.Background(), p, TxSettings{ReadOnly: true, Snapshot: true}, false,
+		func(context.Context, *Transaction) error {
+			return nil
+		},
+	)
+	if err != nil {
+		t.Fatalf("RetryTx() = %v; want nil", err)
+	}
+	if committed {
+		t.Errorf("read-only snapshot transaction should skip the commit round-trip")
+	}
+	if rolledBack {
+		t.Errorf("successful read-only snapshot transaction was rolled back")
+	}
+}
@@ -0,0 +1,86 @@
+package table
+
+import (
+	"context"
+	"sync"
+)
+
+// Session is the subset of a session handle retryBackoff and RetryTx need:
+// an identity, a closed flag the pool checks before reusing it, and
+// fakeable transaction behavior. The full Session (query execution, the
+// real transport calls) lives outside this chunk's checkout.
+type Session struct {
+	id      string
+	onClose func()
+
+	closeMux sync.Mutex
+	closed   bool
+
+	// onBeginTransaction, onCommitTx and onRollback let tests fake
+	// transaction behavior per Session the same way SessionProviderFunc
+	// lets them fake OnGet/OnPut/OnRetry; a production session installs
+	// the real transport calls here instead.
+	onBeginTransaction func(ctx context.Context, settings TxSettings) (*Transaction, error)
+	onCommitTx         func(ctx context.Context, tx *Transaction) error
+	onRollback         func(ctx context.Context, tx *Transaction) error
+}
+
+func newSession(onClose func(), id string) *Session {
+	return &Session{id: id, onClose: onClose}
+}
+
+func simpleSession() *Session {
+	return newSession(nil, "simple")
+}
+
+// close marks the session closed and, if set, notifies onClose. Idempotent:
+// a session already closed is left alone.
+func (s *Session) close() {
+	s.closeMux.Lock()
+	defer s.closeMux.Unlock()
+	if s.closed {
+		return
+	}
+	s.closed = true
+	if s.onClose != nil {
+		s.onClose()
+	}
+}
+
+// TxSettings configures a transaction opened by Session.BeginTransaction.
+type TxSettings struct {
+	ReadOnly bool
+	// Snapshot requests a read-only snapshot isolation level. It only has
+	// an effect when ReadOnly is also set.
+	Snapshot bool
+}
+
+// Transaction is a transaction opened against a Session.
+type Transaction struct {
+	session  *Session
+	Settings TxSettings
+}
+
+// BeginTransaction opens a transaction with settings against s.
+func (s *Session) BeginTransaction(ctx context.Context, settings TxSettings) (*Transaction, error) {
+	if s.onBeginTransaction != nil {
+		return s.onBeginTransaction(ctx, settings)
+	}
+	return &Transaction{session: s, Settings: settings}, nil
+}
+
+// CommitTx commits tx.
+func (tx *Transaction) CommitTx(ctx context.Context) (*Transaction, error) {
+	if tx.session.onCommitTx != nil {
+		return tx, tx.session.onCommitTx(ctx, tx)
+	}
+	return tx, nil
+}
+
+// Rollback rolls tx back.
+func (tx *Transaction) Rollback(ctx context.Context) error {
+	if tx.session.onRollback != nil {
+		return tx.session.onRollback(ctx, tx)
+	}
+	return nil
+}
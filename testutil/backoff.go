@@ -0,0 +1,24 @@
+// Package testutil collects small test doubles shared by the SDK's own test
+// suites.
+package testutil
+
+import (
+	"context"
+	"time"
+)
+
+// BackoffFunc adapts the legacy "return a channel that fires when the wait
+// is over" backoff shape to backoff.Backoff, so tests that need to observe
+// a retry's wait in flight - by blocking on a channel the func sends before
+// returning it - keep working against the deadline-aware Backoff interface:
+// Delay blocks until either the channel f(attempt) returns fires or ctx
+// ends, whichever happens first, then reports no further delay is needed.
+type BackoffFunc func(attempt int) <-chan time.Time
+
+func (f BackoffFunc) Delay(attempt int, ctx context.Context) time.Duration {
+	select {
+	case <-f(attempt):
+	case <-ctx.Done():
+	}
+	return 0
+}
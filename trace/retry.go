@@ -0,0 +1,75 @@
+// Package trace holds the SDK's tracing hook types: structs of optional
+// callbacks that observe what an operation is doing without being able to
+// change its outcome, attached to a context.Context and read back out of it
+// by the code being traced.
+package trace
+
+import (
+	"context"
+	"time"
+)
+
+type retryTraceKey struct{}
+
+// RetryTrace observes one SessionProvider.Retry (or RetryTx) loop. Every
+// field is optional; a nil hook is simply not called.
+type RetryTrace struct {
+	// OnRetry is called once when the loop starts. The func it returns, if
+	// non-nil, is called once when the loop finishes.
+	OnRetry func(RetryLoopStartInfo) func(RetryLoopDoneInfo)
+
+	// OnBackoff is called every time the loop computes a delay before
+	// retrying, after that delay has already been capped to the context's
+	// remaining deadline - so OnBackoff always reports the delay the loop
+	// will actually wait.
+	OnBackoff func(RetryBackoffInfo)
+
+	// OnTxRestart is called by RetryTx whenever it restarts the whole
+	// transaction instead of retrying a single statement. A plain Retry
+	// loop never calls it, since there every attempt is already the
+	// smallest unit of work.
+	OnTxRestart func(RetryTxRestartInfo)
+}
+
+// RetryLoopStartInfo is reported once when a retry loop starts.
+type RetryLoopStartInfo struct {
+	Context context.Context
+}
+
+// RetryLoopDoneInfo is reported once when a retry loop finishes, whether it
+// succeeded, returned a terminal error, or gave up.
+type RetryLoopDoneInfo struct {
+	Context  context.Context
+	Latency  time.Duration
+	Attempts int
+	Error    error
+}
+
+// RetryBackoffInfo reports one computed retry delay and the attempt it
+// applies to.
+type RetryBackoffInfo struct {
+	Context context.Context
+	Attempt int
+	Delay   time.Duration
+}
+
+// RetryTxRestartInfo reports that RetryTx is about to restart the whole
+// transaction as Attempt (1-based) because the previous attempt failed with
+// Error, an error the retry loop classified as retryable.
+type RetryTxRestartInfo struct {
+	Context context.Context
+	Attempt int
+	Error   error
+}
+
+// WithRetryTrace attaches t to ctx, replacing any RetryTrace already there.
+func WithRetryTrace(ctx context.Context, t RetryTrace) context.Context {
+	return context.WithValue(ctx, retryTraceKey{}, t)
+}
+
+// ContextRetryTrace returns the RetryTrace attached to ctx, or the zero
+// RetryTrace (every hook nil) if none was attached.
+func ContextRetryTrace(ctx context.Context) RetryTrace {
+	t, _ := ctx.Value(retryTraceKey{}).(RetryTrace)
+	return t
+}
@@ -0,0 +1,120 @@
+// Package ydberr exposes the SDK's error types and sentinel values as
+// plain Go errors so callers can use the standard errors.Is/errors.As
+// instead of the bool/code/name tuples returned by the older ydb.IsXxx
+// helpers.
+package ydberr
+
+import (
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/errors"
+)
+
+// TransportError is returned (possibly wrapped) whenever a gRPC call to YDB
+// fails at the transport level. Use errors.As to recover it from a wrapped
+// error:
+//
+//	var te *ydberr.TransportError
+//	if errors.As(err, &te) {
+//		log.Println(te.Reason)
+//	}
+type TransportError = errors.TransportError
+
+// OpError is returned (possibly wrapped) whenever a YDB operation completes
+// with a non-success status.
+type OpError = errors.OpError
+
+// sentinel is a comparable error value that matches any TransportError or
+// OpError carrying a matching reason code, no matter how many times the
+// error has been wrapped with fmt.Errorf("...: %w", err). Sentinels are
+// meant to be compared against with errors.Is; they are never returned by
+// the SDK themselves.
+//
+// The matching logic lives in internal/errors.TransportError.Is and
+// OpError.Is, not here: errors.Is(err, target) walks err's chain and calls
+// Is on each error it finds there, asking "does err match target?" - it
+// never calls a method on target itself. So sentinel implements
+// errors.ReasonMatcher, the interface TransportError.Is/OpError.Is check
+// for, rather than its own Is method, which errors.Is would never reach.
+type sentinel struct {
+	name            string
+	hasTransport    bool
+	transportReason errors.TransportErrorCode
+	hasOp           bool
+	opReason        errors.StatusCode
+}
+
+func (s *sentinel) Error() string {
+	return s.name
+}
+
+func (s *sentinel) MatchesTransportError(reason errors.TransportErrorCode) bool {
+	return s.hasTransport && reason == s.transportReason
+}
+
+func (s *sentinel) MatchesOpError(reason errors.StatusCode) bool {
+	return s.hasOp && reason == s.opReason
+}
+
+// transportAndOp builds a sentinel that matches either a transport error or
+// an operation error reporting the same logical status, since YDB may
+// surface the same condition at either layer depending on the transport.
+func transportAndOp(name string, transportReason errors.TransportErrorCode, opReason errors.StatusCode) error {
+	return &sentinel{
+		name:            name,
+		hasTransport:    true,
+		transportReason: transportReason,
+		hasOp:           true,
+		opReason:        opReason,
+	}
+}
+
+func transportOnly(name string, reason errors.TransportErrorCode) error {
+	return &sentinel{name: name, hasTransport: true, transportReason: reason}
+}
+
+func opOnly(name string, reason errors.StatusCode) error {
+	return &sentinel{name: name, hasOp: true, opReason: reason}
+}
+
+// Sentinels for conditions the SDK can surface at either the transport or
+// the operation layer. Overloaded and ResourceExhausted deliberately don't
+// overlap even though both stem from the server being over capacity:
+// ResourceExhausted is the transport-level gRPC code, Overloaded is the
+// operation-level status YDB reports once a call reaches it, and a given
+// error only ever carries one or the other.
+var (
+	Unavailable       = transportAndOp("ydberr: unavailable", errors.TransportErrorUnavailable, errors.StatusUnavailable)
+	Aborted           = transportAndOp("ydberr: aborted", errors.TransportErrorAborted, errors.StatusAborted)
+	ResourceExhausted = transportOnly("ydberr: resource exhausted", errors.TransportErrorResourceExhausted)
+	PermissionDenied  = transportOnly("ydberr: permission denied", errors.TransportErrorPermissionDenied)
+	NotFound          = transportAndOp("ydberr: not found", errors.TransportErrorNotFound, errors.StatusNotFound)
+	AlreadyExists     = transportAndOp("ydberr: already exists", errors.TransportErrorAlreadyExists, errors.StatusAlreadyExists)
+)
+
+// Sentinels for conditions that only make sense at the operation layer.
+var (
+	Overloaded         = opOnly("ydberr: overloaded", errors.StatusOverloaded)
+	BadSession         = opOnly("ydberr: bad session", errors.StatusBadSession)
+	SessionExpired     = opOnly("ydberr: session expired", errors.StatusSessionExpired)
+	SessionBusy        = opOnly("ydberr: session busy", errors.StatusSessionBusy)
+	Undetermined       = opOnly("ydberr: undetermined", errors.StatusUndetermined)
+	PreconditionFailed = opOnly("ydberr: precondition failed", errors.StatusPreconditionFailed)
+)
+
+// Timeout matches a deadline being exceeded, whether reported as a
+// transport-level DeadlineExceeded or an operation-level StatusTimeout -
+// the same pair IsTimeoutError already checks.
+var Timeout = transportAndOp("ydberr: timeout", errors.TransportErrorDeadlineExceeded, errors.StatusTimeout)
+
+// Is reports whether err matches target, unwrapping err as needed. It is a
+// thin alias over the standard library so callers don't need a second
+// import when composing ydberr sentinels with errors.Is.
+func Is(err, target error) bool {
+	return errors.Is(err, target)
+}
+
+// As finds the first error in err's chain that matches target, unwrapping
+// err as needed. See errors.As for the assignability rules target must
+// satisfy.
+func As(err error, target interface{}) bool {
+	return errors.As(err, target)
+}
@@ -0,0 +1,61 @@
+package ydberr_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	ierrors "github.com/ydb-platform/ydb-go-sdk/v3/internal/errors"
+	"github.com/ydb-platform/ydb-go-sdk/v3/ydberr"
+)
+
+func TestSentinelsMatchThroughWrapping(t *testing.T) {
+	for name, tt := range map[string]struct {
+		err    error
+		target error
+		want   bool
+	}{
+		"op bad session matches BadSession": {
+			err:    ierrors.NewOpError(ierrors.WithOEReason(ierrors.StatusBadSession)),
+			target: ydberr.BadSession,
+			want:   true,
+		},
+		"wrapped op bad session matches BadSession": {
+			err: fmt.Errorf("query %q: %w", "SELECT 1",
+				ierrors.NewOpError(ierrors.WithOEReason(ierrors.StatusBadSession))),
+			target: ydberr.BadSession,
+			want:   true,
+		},
+		"op overloaded does not match BadSession": {
+			err:    ierrors.NewOpError(ierrors.WithOEReason(ierrors.StatusOverloaded)),
+			target: ydberr.BadSession,
+			want:   false,
+		},
+		"transport resource exhausted matches ResourceExhausted": {
+			err:    ierrors.NewTransportError(ierrors.TransportErrorResourceExhausted),
+			target: ydberr.ResourceExhausted,
+			want:   true,
+		},
+		"transport resource exhausted does not match Overloaded": {
+			err:    ierrors.NewTransportError(ierrors.TransportErrorResourceExhausted),
+			target: ydberr.Overloaded,
+			want:   false,
+		},
+		"op overloaded matches Overloaded": {
+			err:    ierrors.NewOpError(ierrors.WithOEReason(ierrors.StatusOverloaded)),
+			target: ydberr.Overloaded,
+			want:   true,
+		},
+		"transport deadline exceeded matches Timeout": {
+			err:    ierrors.NewTransportError(ierrors.TransportErrorDeadlineExceeded),
+			target: ydberr.Timeout,
+			want:   true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if got := errors.Is(tt.err, tt.target); got != tt.want {
+				t.Errorf("errors.Is() = %v; want %v", got, tt.want)
+			}
+		})
+	}
+}